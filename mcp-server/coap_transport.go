@@ -0,0 +1,249 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "encoding/json"
+    "fmt"
+    "net"
+    "os"
+    "sync"
+
+    coap "github.com/plgd-dev/go-coap/v3"
+    piondtls "github.com/pion/dtls/v3"
+
+    "github.com/plgd-dev/go-coap/v3/message"
+    "github.com/plgd-dev/go-coap/v3/message/codes"
+    "github.com/plgd-dev/go-coap/v3/message/pool"
+    "github.com/plgd-dev/go-coap/v3/mux"
+
+    "github.com/mark3labs/mcp-go/server"
+)
+
+// mcpResource is the CoAP resource path that JSON-RPC requests are POSTed
+// to, the CoAP counterpart of the message endpoint the SSE/mTLS transports
+// accept JSON-RPC POSTs on.
+const mcpResource = "/mcp"
+
+// coapConfig holds the transport security settings assembled from CoAPOptions.
+type coapConfig struct {
+    network     string // "udp" (plain CoAP) or "udp-dtls" (CoAP over DTLS)
+    pskIdentity string
+    pskKey      []byte
+    certFile    string
+    keyFile     string
+    caFile      string
+}
+
+// CoAPOption configures ServeCoAP, following the functional-options pattern
+// used for the other optional transports in this package.
+type CoAPOption func(*coapConfig)
+
+// WithDTLSPSK enables DTLS using a pre-shared key, the lightweight mode most
+// constrained devices use when certificates are too expensive to provision.
+func WithDTLSPSK(identity string, key []byte) CoAPOption {
+    return func(c *coapConfig) {
+        c.network = "udp-dtls"
+        c.pskIdentity = identity
+        c.pskKey = key
+    }
+}
+
+// WithDTLSCertificate enables DTLS using a server certificate/key pair and a
+// CA bundle to verify clients, for devices capable of full X.509 DTLS.
+func WithDTLSCertificate(certFile, keyFile, caFile string) CoAPOption {
+    return func(c *coapConfig) {
+        c.network = "udp-dtls"
+        c.certFile = certFile
+        c.keyFile = keyFile
+        c.caFile = caFile
+    }
+}
+
+// ServeCoAP exposes s over CoAP on addr (UDP, optionally DTLS), so MCP tools
+// can be called from constrained/IoT clients for whom HTTP+TLS is too heavy.
+// Requests are plain JSON-RPC payloads POSTed to mcpResource; the go-coap
+// library negotiates block-wise transfer automatically for payloads larger
+// than the path MTU. helloHandler and every other tool work unchanged, since
+// only the framing differs from the stdio/SSE/mTLS transports.
+func ServeCoAP(s *server.MCPServer, addr string, opts ...CoAPOption) error {
+    cfg := coapConfig{network: "udp"}
+    for _, opt := range opts {
+        opt(&cfg)
+    }
+
+    router := mux.NewRouter()
+    if err := router.Handle(mcpResource, mux.HandlerFunc(coapHandler(s))); err != nil {
+        return fmt.Errorf("register %s handler: %w", mcpResource, err)
+    }
+
+    switch cfg.network {
+    case "udp":
+        fmt.Printf("👋 Server started (coap) on %s\n", addr)
+        return coap.ListenAndServe("udp", addr, router)
+    case "udp-dtls":
+        dtlsConfig, err := cfg.buildDTLSConfig()
+        if err != nil {
+            return fmt.Errorf("build DTLS config: %w", err)
+        }
+        fmt.Printf("👋 Server started (coaps) on %s\n", addr)
+        return coap.ListenAndServeDTLS("udp", addr, dtlsConfig, router)
+    default:
+        return fmt.Errorf("unknown coap network %q", cfg.network)
+    }
+}
+
+func (cfg coapConfig) buildDTLSConfig() (*piondtls.Config, error) {
+    if cfg.pskKey != nil {
+        return &piondtls.Config{
+            PSK: func(hint []byte) ([]byte, error) { return cfg.pskKey, nil },
+            PSKIdentityHint:      []byte(cfg.pskIdentity),
+            CipherSuites:         []piondtls.CipherSuiteID{piondtls.TLS_PSK_WITH_AES_128_CCM_8},
+            ExtendedMasterSecret: piondtls.RequireExtendedMasterSecret,
+        }, nil
+    }
+
+    cert, err := tls.LoadX509KeyPair(cfg.certFile, cfg.keyFile)
+    if err != nil {
+        return nil, fmt.Errorf("load server cert/key: %w", err)
+    }
+    caPEM, err := os.ReadFile(cfg.caFile)
+    if err != nil {
+        return nil, fmt.Errorf("read CA bundle: %w", err)
+    }
+    clientCAs := x509.NewCertPool()
+    if !clientCAs.AppendCertsFromPEM(caPEM) {
+        return nil, fmt.Errorf("no certificates found in %s", cfg.caFile)
+    }
+    return &piondtls.Config{
+        Certificates:         []tls.Certificate{cert},
+        ClientCAs:            clientCAs,
+        ClientAuth:           piondtls.RequireAndVerifyClientCert,
+        ExtendedMasterSecret: piondtls.RequireExtendedMasterSecret,
+    }, nil
+}
+
+// coapHandler adapts CoAP POST requests on mcpResource into calls against
+// the MCP server's JSON-RPC message dispatcher, and also maintains the set
+// of clients Observe-ing mcpResource so every tool call's result is pushed
+// to them as a notification without the client needing to poll. A GET
+// without the Observe option deregisters a previously-observing client,
+// per the CoAP Observe deregistration convention (RFC 7641 §3.6).
+func coapHandler(s *server.MCPServer) mux.HandlerFunc {
+    observers := newObserverSet()
+
+    return func(w mux.ResponseWriter, r *mux.Message) {
+        ctx := r.Context()
+
+        switch r.Code() {
+        case codes.POST:
+            body, err := r.ReadBody()
+            if err != nil {
+                w.SetResponse(codes.BadRequest, message.TextPlain, nil)
+                return
+            }
+            respBody, code := handlePost(ctx, s, observers, body)
+            if code != codes.Content {
+                w.SetResponse(code, message.TextPlain, nil)
+                return
+            }
+            w.SetResponse(code, message.AppJSON, bytes.NewReader(respBody))
+        case codes.GET:
+            if obs, err := r.Options().Observe(); err == nil {
+                observers.add(uint64(obs), w.Conn(), r.Token())
+                w.SetResponse(codes.Content, message.AppJSON, bytes.NewReader([]byte(`{"status":"observing"}`)))
+                return
+            }
+            observers.remove(w.Conn())
+            w.SetResponse(codes.Content, message.AppJSON, bytes.NewReader([]byte(`{"status":"not observing"}`)))
+        default:
+            w.SetResponse(codes.MethodNotAllowed, message.TextPlain, nil)
+        }
+    }
+}
+
+// handlePost runs a JSON-RPC tool call through s and broadcasts its result
+// to every client currently Observe-ing mcpResource, so streamed tool
+// results piggyback on the normal request/response path instead of needing
+// a separate push mechanism. It is split out from coapHandler so the
+// dispatch/notify logic can be unit tested without a real CoAP connection.
+func handlePost(ctx context.Context, s *server.MCPServer, observers *observerSet, body []byte) ([]byte, codes.Code) {
+    resp := s.HandleMessage(ctx, body)
+    respBody, err := json.Marshal(resp)
+    if err != nil {
+        return nil, codes.InternalServerError
+    }
+    observers.notify(ctx, respBody)
+    return respBody, codes.Content
+}
+
+// observeConn is the subset of mux.Conn that observerSet needs to push a
+// notification to an observing client. Keeping it narrow (rather than
+// depending on mux.Conn directly) lets tests exercise add/remove/notify
+// against a fake, without a real CoAP connection.
+type observeConn interface {
+    RemoteAddr() net.Addr
+    AcquireMessage(ctx context.Context) *pool.Message
+    WriteMessage(*pool.Message) error
+    ReleaseMessage(*pool.Message)
+}
+
+// observerSet tracks clients that issued a CoAP Observe request against
+// mcpResource, so streamed tool results/notifications can be pushed to them.
+type observerSet struct {
+    mu        sync.Mutex
+    observers map[string]observer
+}
+
+type observer struct {
+    seq   uint32
+    conn  observeConn
+    token message.Token
+}
+
+func newObserverSet() *observerSet {
+    return &observerSet{observers: make(map[string]observer)}
+}
+
+func (o *observerSet) add(seq uint64, conn observeConn, token message.Token) {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    o.observers[conn.RemoteAddr().String()] = observer{seq: uint32(seq), conn: conn, token: token}
+}
+
+// remove deregisters conn, e.g. in response to a plain GET (no Observe
+// option) from a client that was previously observing.
+func (o *observerSet) remove(conn observeConn) {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    delete(o.observers, conn.RemoteAddr().String())
+}
+
+// count reports how many clients are currently observing, for tests.
+func (o *observerSet) count() int {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    return len(o.observers)
+}
+
+// notify pushes payload to every observer as a CoAP notification; handlePost
+// calls it with the JSON-RPC response of every POSTed request.
+func (o *observerSet) notify(ctx context.Context, payload []byte) {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    for addr, obs := range o.observers {
+        msg := obs.conn.AcquireMessage(ctx)
+        msg.SetCode(codes.Content)
+        msg.SetToken(obs.token)
+        msg.SetContentFormat(message.AppJSON)
+        msg.SetBody(bytes.NewReader(payload))
+        obs.seq++
+        msg.SetObserve(uint32(obs.seq))
+        if err := obs.conn.WriteMessage(msg); err != nil {
+            delete(o.observers, addr)
+        }
+        obs.conn.ReleaseMessage(msg)
+    }
+}
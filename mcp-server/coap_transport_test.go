@@ -0,0 +1,111 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net"
+    "sync"
+    "testing"
+
+    "github.com/plgd-dev/go-coap/v3/message"
+    "github.com/plgd-dev/go-coap/v3/message/codes"
+    "github.com/plgd-dev/go-coap/v3/message/pool"
+
+    "github.com/mark3labs/mcp-go/server"
+)
+
+type fakeAddr string
+
+func (a fakeAddr) Network() string { return "udp" }
+func (a fakeAddr) String() string  { return string(a) }
+
+// fakeObserveConn implements observeConn so observerSet can be exercised
+// without a real CoAP connection.
+type fakeObserveConn struct {
+    addr      fakeAddr
+    failWrite bool
+
+    mu   sync.Mutex
+    sent []*pool.Message
+}
+
+func (c *fakeObserveConn) RemoteAddr() net.Addr { return c.addr }
+
+func (c *fakeObserveConn) AcquireMessage(ctx context.Context) *pool.Message {
+    return pool.NewMessage(ctx)
+}
+
+func (c *fakeObserveConn) ReleaseMessage(*pool.Message) {}
+
+func (c *fakeObserveConn) WriteMessage(m *pool.Message) error {
+    if c.failWrite {
+        return errors.New("write failed")
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.sent = append(c.sent, m)
+    return nil
+}
+
+func (c *fakeObserveConn) sentCount() int {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return len(c.sent)
+}
+
+func TestObserverSetNotifyPushesToObservers(t *testing.T) {
+    observers := newObserverSet()
+    conn := &fakeObserveConn{addr: "client-1"}
+    observers.add(1, conn, message.Token("tok"))
+
+    observers.notify(context.Background(), []byte(`{"result":"ok"}`))
+
+    if got := conn.sentCount(); got != 1 {
+        t.Fatalf("got %d notifications, want 1", got)
+    }
+}
+
+func TestObserverSetNotifyDropsFailingObserver(t *testing.T) {
+    observers := newObserverSet()
+    conn := &fakeObserveConn{addr: "client-1", failWrite: true}
+    observers.add(1, conn, message.Token("tok"))
+
+    observers.notify(context.Background(), []byte(`{}`))
+
+    if got := observers.count(); got != 0 {
+        t.Fatalf("got %d observers after a failed write, want 0", got)
+    }
+}
+
+func TestObserverSetRemove(t *testing.T) {
+    observers := newObserverSet()
+    conn := &fakeObserveConn{addr: "client-1"}
+    observers.add(1, conn, message.Token("tok"))
+
+    observers.remove(conn)
+
+    if got := observers.count(); got != 0 {
+        t.Fatalf("got %d observers after remove, want 0", got)
+    }
+}
+
+func TestHandlePostNotifiesObservers(t *testing.T) {
+    s := server.NewMCPServer("test", "0.0.0")
+    observers := newObserverSet()
+    conn := &fakeObserveConn{addr: "client-1"}
+    observers.add(1, conn, message.Token("tok"))
+
+    req := map[string]any{"jsonrpc": "2.0", "id": 1, "method": "ping"}
+    body, err := json.Marshal(req)
+    if err != nil {
+        t.Fatalf("marshal request: %v", err)
+    }
+
+    if _, code := handlePost(context.Background(), s, observers, body); code != codes.Content {
+        t.Fatalf("got code %v, want codes.Content", code)
+    }
+    if got := conn.sentCount(); got != 1 {
+        t.Fatalf("got %d notifications pushed to the observer, want 1", got)
+    }
+}
@@ -2,13 +2,26 @@ package main
 
 import (
     "context"
+    "flag"
     "fmt"
+    "log/slog"
+    "os"
+    "strings"
 
     "github.com/mark3labs/mcp-go/mcp"
     "github.com/mark3labs/mcp-go/server"
 )
 
 func main() {
+    transport := flag.String("transport", "stdio", "transport to serve the MCP server on: stdio, sse, mtls or coap")
+    addr := flag.String("addr", ":8080", "address to bind the HTTP/CoAP-based transports to (sse/mtls/coap)")
+    tlsCert := flag.String("tls-cert", "", "server certificate path (mtls/coap transports)")
+    tlsKey := flag.String("tls-key", "", "server private key path (mtls/coap transports)")
+    tlsCA := flag.String("tls-ca", "", "CA bundle used to verify client certificates (mtls/coap transports)")
+    coapPSKIdentity := flag.String("coap-psk-identity", "", "DTLS PSK identity hint (coap transport, PSK mode)")
+    coapPSKKey := flag.String("coap-psk-key", "", "DTLS PSK key (coap transport, PSK mode)")
+    flag.Parse()
+
     // Create MCP server
     s := server.NewMCPServer(
         "hello-mcp-server",
@@ -26,10 +39,39 @@ func main() {
         ),
     )
 
-    // Add tool handler
-    s.AddTool(tool, helloHandler)
+    // Add tool handler, wrapped with the shared logging/recovery pipeline
+    AddToolWithMiddleware(s, tool, helloHandler, WithRecover(), WithLogger(slog.Default()))
+
+    // shout_world: registered via RegisterFunc as a one-liner, with its
+    // schema and argument decoding derived from shoutArgs by reflection.
+    RegisterFunc(s, "shout_world", "Say hello to someone, loudly", shoutHandler)
+
+    switch *transport {
+    case "sse":
+        serveSSE(s, *addr)
+    case "mtls":
+        serveMTLS(s, mtlsConfig{addr: *addr, certFile: *tlsCert, keyFile: *tlsKey, caFile: *tlsCA})
+    case "coap":
+        var coapOpts []CoAPOption
+        switch {
+        case *coapPSKIdentity != "":
+            coapOpts = append(coapOpts, WithDTLSPSK(*coapPSKIdentity, []byte(*coapPSKKey)))
+        case *tlsCert != "" || *tlsKey != "" || *tlsCA != "":
+            coapOpts = append(coapOpts, WithDTLSCertificate(*tlsCert, *tlsKey, *tlsCA))
+        }
+        if err := ServeCoAP(s, *addr, coapOpts...); err != nil {
+            fmt.Printf("Server error: %v\n", err)
+        }
+    case "stdio":
+        serveStdio(s)
+    default:
+        fmt.Printf("unknown transport %q, must be stdio, sse, mtls or coap\n", *transport)
+        os.Exit(1)
+    }
+}
 
-    fmt.Println("👋 Server started")
+func serveStdio(s *server.MCPServer) {
+    fmt.Println("👋 Server started (stdio)")
     // Start the stdio server
     if err := server.ServeStdio(s); err != nil {
         fmt.Printf("Server error: %v\n", err)
@@ -37,6 +79,19 @@ func main() {
     fmt.Println("Server stopped")
 }
 
+// serveSSE exposes the MCP server over HTTP using Server-Sent Events, so
+// remote clients (Claude Desktop, VS Code) can reach the same tools that
+// serveStdio offers over a local stdio child process.
+func serveSSE(s *server.MCPServer, addr string) {
+    sseServer := server.NewSSEServer(s, server.WithBaseURL("http://localhost"+addr))
+
+    fmt.Printf("👋 Server started (sse) on %s\n", addr)
+    if err := sseServer.Start(addr); err != nil {
+        fmt.Printf("Server error: %v\n", err)
+    }
+    fmt.Println("Server stopped")
+}
+
 func helloHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 
     name, ok := request.Params.Arguments["name"].(string)
@@ -44,5 +99,19 @@ func helloHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallTo
         return mcp.NewToolResultError("name must be a string"), nil
     }
 
+    if subject, ok := peerSubjectFromContext(ctx); ok {
+        return mcp.NewToolResultText(fmt.Sprintf("Hello, %s! (verified as %s)", name, subject)), nil
+    }
+
     return mcp.NewToolResultText(fmt.Sprintf("Hello, %s!", name)), nil
+}
+
+// shoutArgs is decoded from the tool call's arguments by RegisterFunc, which
+// also derives shout_world's schema from these mcp tags.
+type shoutArgs struct {
+    Name string `mcp:"name,required,desc=Name of the person to greet"`
+}
+
+func shoutHandler(ctx context.Context, args shoutArgs) (string, error) {
+    return strings.ToUpper(fmt.Sprintf("Hello, %s!", args.Name)), nil
 }
\ No newline at end of file
@@ -0,0 +1,71 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "log/slog"
+    "time"
+
+    "github.com/mark3labs/mcp-go/mcp"
+    "github.com/mark3labs/mcp-go/server"
+)
+
+// ToolHandler is the signature every MCP tool handler implements. It is kept
+// as a local alias of server.ToolHandlerFunc so middleware can be written
+// once and reused regardless of how a handler was registered.
+type ToolHandler = server.ToolHandlerFunc
+
+// ToolMiddleware wraps a ToolHandler with cross-cutting behavior (logging,
+// timing, panic recovery, auth, rate limiting, ...) and returns the wrapped
+// handler. Middlewares compose the same way http.Handler middleware does.
+type ToolMiddleware func(next ToolHandler) ToolHandler
+
+// Chain applies mws to next in order, so the first middleware in the list is
+// the outermost one: Chain(a, b)(h) runs a, then b, then h.
+func Chain(mws ...ToolMiddleware) ToolMiddleware {
+    return func(next ToolHandler) ToolHandler {
+        for i := len(mws) - 1; i >= 0; i-- {
+            next = mws[i](next)
+        }
+        return next
+    }
+}
+
+// AddToolWithMiddleware registers tool on s with mws applied around handler,
+// so new tools can opt into the same pipeline without repeating boilerplate.
+func AddToolWithMiddleware(s *server.MCPServer, tool mcp.Tool, handler ToolHandler, mws ...ToolMiddleware) {
+    s.AddTool(tool, Chain(mws...)(handler))
+}
+
+// WithLogger logs the tool name, duration and error (if any) of every call.
+func WithLogger(logger *slog.Logger) ToolMiddleware {
+    return func(next ToolHandler) ToolHandler {
+        return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+            start := time.Now()
+            result, err := next(ctx, request)
+            logger.Info("tool call",
+                "tool", request.Params.Name,
+                "duration", time.Since(start),
+                "error", err,
+            )
+            return result, err
+        }
+    }
+}
+
+// WithRecover turns a panic inside next into a tool error result instead of
+// crashing the server, so a single misbehaving handler can't take down the
+// process.
+func WithRecover() ToolMiddleware {
+    return func(next ToolHandler) ToolHandler {
+        return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+            defer func() {
+                if r := recover(); r != nil {
+                    result = mcp.NewToolResultError(fmt.Sprintf("panic: %v", r))
+                    err = nil
+                }
+            }()
+            return next(ctx, request)
+        }
+    }
+}
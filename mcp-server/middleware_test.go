@@ -0,0 +1,69 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestChainOrdering(t *testing.T) {
+    var calls []string
+
+    record := func(name string) ToolMiddleware {
+        return func(next ToolHandler) ToolHandler {
+            return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+                calls = append(calls, name+":before")
+                result, err := next(ctx, request)
+                calls = append(calls, name+":after")
+                return result, err
+            }
+        }
+    }
+
+    handler := Chain(record("a"), record("b"))(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        calls = append(calls, "handler")
+        return mcp.NewToolResultText("ok"), nil
+    })
+
+    if _, err := handler(context.Background(), mcp.CallToolRequest{}); err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    want := []string{"a:before", "b:before", "handler", "b:after", "a:after"}
+    if len(calls) != len(want) {
+        t.Fatalf("got %v, want %v", calls, want)
+    }
+    for i := range want {
+        if calls[i] != want[i] {
+            t.Fatalf("got %v, want %v", calls, want)
+        }
+    }
+}
+
+func TestChainPropagatesError(t *testing.T) {
+    wantErr := errors.New("boom")
+    handler := Chain(WithRecover())(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        return nil, wantErr
+    })
+
+    _, err := handler(context.Background(), mcp.CallToolRequest{})
+    if !errors.Is(err, wantErr) {
+        t.Fatalf("got error %v, want %v", err, wantErr)
+    }
+}
+
+func TestWithRecoverCatchesPanic(t *testing.T) {
+    handler := Chain(WithRecover())(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        panic("kaboom")
+    })
+
+    result, err := handler(context.Background(), mcp.CallToolRequest{})
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if result == nil || !result.IsError {
+        t.Fatalf("expected an error result, got %+v", result)
+    }
+}
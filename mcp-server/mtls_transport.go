@@ -0,0 +1,152 @@
+package main
+
+import (
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "fmt"
+    "net/http"
+    "os"
+    "sync/atomic"
+    "time"
+
+    "github.com/mark3labs/mcp-go/server"
+)
+
+// peerSubjectKey is the context key under which the verified client
+// certificate's subject (CN/SAN) is stored for the duration of a request.
+type peerSubjectKey struct{}
+
+// peerSubjectFromContext returns the subject of the client certificate that
+// authenticated the current request, if any. helloHandler uses this to
+// personalize greetings on the mtls transport; it does not reject callers.
+func peerSubjectFromContext(ctx context.Context) (string, bool) {
+    subject, ok := ctx.Value(peerSubjectKey{}).(string)
+    return subject, ok
+}
+
+// rotatingCertificate re-reads a server certificate/key pair from disk at a
+// fixed interval (see watch), whether or not the files changed, so short-lived
+// certs issued by an ACME-style CA can rotate without restarting the process.
+type rotatingCertificate struct {
+    certFile string
+    keyFile  string
+    current  atomic.Pointer[tls.Certificate]
+}
+
+func newRotatingCertificate(certFile, keyFile string) (*rotatingCertificate, error) {
+    rc := &rotatingCertificate{certFile: certFile, keyFile: keyFile}
+    if err := rc.reload(); err != nil {
+        return nil, err
+    }
+    return rc, nil
+}
+
+func (rc *rotatingCertificate) reload() error {
+    cert, err := tls.LoadX509KeyPair(rc.certFile, rc.keyFile)
+    if err != nil {
+        return fmt.Errorf("load server cert/key: %w", err)
+    }
+    rc.current.Store(&cert)
+    return nil
+}
+
+// watch periodically re-reads the certificate/key pair from disk, picking up
+// rotations performed out-of-band (e.g. by an ACME client) every interval.
+func (rc *rotatingCertificate) watch(interval time.Duration, stop <-chan struct{}) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-stop:
+            return
+        case <-ticker.C:
+            if err := rc.reload(); err != nil {
+                fmt.Printf("cert rotation: %v\n", err)
+            }
+        }
+    }
+}
+
+func (rc *rotatingCertificate) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+    return rc.current.Load(), nil
+}
+
+// mtlsConfig groups the on-disk material needed to serve MCP over mTLS.
+type mtlsConfig struct {
+    addr     string
+    certFile string
+    keyFile  string
+    caFile   string
+}
+
+// serveMTLS exposes the MCP server over HTTP with mutual TLS: the client
+// must present a certificate signed by caFile, and helloHandler can read the
+// verified peer's subject back out of the request context.
+func serveMTLS(s *server.MCPServer, cfg mtlsConfig) {
+    caPEM, err := os.ReadFile(cfg.caFile)
+    if err != nil {
+        fmt.Printf("Server error: read CA bundle: %v\n", err)
+        return
+    }
+    clientCAs := x509.NewCertPool()
+    if !clientCAs.AppendCertsFromPEM(caPEM) {
+        fmt.Printf("Server error: no certificates found in %s\n", cfg.caFile)
+        return
+    }
+
+    rotatingCert, err := newRotatingCertificate(cfg.certFile, cfg.keyFile)
+    if err != nil {
+        fmt.Printf("Server error: %v\n", err)
+        return
+    }
+    stop := make(chan struct{})
+    defer close(stop)
+    go rotatingCert.watch(time.Minute, stop)
+
+    tlsConfig := &tls.Config{
+        GetCertificate: rotatingCert.getCertificate,
+        ClientAuth:     tls.RequireAndVerifyClientCert,
+        ClientCAs:      clientCAs,
+        MinVersion:     tls.VersionTLS12,
+        CipherSuites: []uint16{
+            tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+            tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+            tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+            tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+            tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+            tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+        },
+    }
+
+    sseServer := server.NewSSEServer(s,
+        server.WithBaseURL("https://localhost"+cfg.addr),
+        server.WithSSEContextFunc(contextWithPeerSubject),
+    )
+
+    httpServer := &http.Server{
+        Addr:      cfg.addr,
+        Handler:   sseServer,
+        TLSConfig: tlsConfig,
+    }
+
+    fmt.Printf("👋 Server started (mtls) on %s\n", cfg.addr)
+    if err := httpServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+        fmt.Printf("Server error: %v\n", err)
+    }
+    fmt.Println("Server stopped")
+}
+
+// contextWithPeerSubject stashes the verified client certificate's subject
+// onto the request context so tool handlers can recover who is calling.
+func contextWithPeerSubject(ctx context.Context, r *http.Request) context.Context {
+    if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+        return ctx
+    }
+    leaf := r.TLS.PeerCertificates[0]
+    subject := leaf.Subject.CommonName
+    if subject == "" && len(leaf.DNSNames) > 0 {
+        subject = leaf.DNSNames[0]
+    }
+    return context.WithValue(ctx, peerSubjectKey{}, subject)
+}
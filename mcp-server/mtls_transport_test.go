@@ -0,0 +1,171 @@
+package main
+
+import (
+    "context"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/tls"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "math/big"
+    "net/http"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// writeSelfSignedCert generates a throwaway self-signed cert/key pair for
+// commonName and writes them as PEM files under dir, returning their paths.
+func writeSelfSignedCert(t *testing.T, dir, commonName string) (certPath, keyPath string) {
+    t.Helper()
+
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatalf("generate key: %v", err)
+    }
+    template := &x509.Certificate{
+        SerialNumber: big.NewInt(1),
+        Subject:      pkix.Name{CommonName: commonName},
+        NotBefore:    time.Unix(0, 0),
+        NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+        KeyUsage:     x509.KeyUsageDigitalSignature,
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+    if err != nil {
+        t.Fatalf("create certificate: %v", err)
+    }
+    keyDER, err := x509.MarshalECPrivateKey(key)
+    if err != nil {
+        t.Fatalf("marshal key: %v", err)
+    }
+
+    certPath = filepath.Join(dir, commonName+"-cert.pem")
+    keyPath = filepath.Join(dir, commonName+"-key.pem")
+    if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+        t.Fatalf("write cert: %v", err)
+    }
+    if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+        t.Fatalf("write key: %v", err)
+    }
+    return certPath, keyPath
+}
+
+func leafCommonName(t *testing.T, cert *tls.Certificate) string {
+    t.Helper()
+    leaf, err := x509.ParseCertificate(cert.Certificate[0])
+    if err != nil {
+        t.Fatalf("parse leaf: %v", err)
+    }
+    return leaf.Subject.CommonName
+}
+
+func TestRotatingCertificatePicksUpChangedFile(t *testing.T) {
+    dir := t.TempDir()
+    certPath, keyPath := writeSelfSignedCert(t, dir, "original")
+
+    rc, err := newRotatingCertificate(certPath, keyPath)
+    if err != nil {
+        t.Fatalf("newRotatingCertificate: %v", err)
+    }
+    cert, err := rc.getCertificate(nil)
+    if err != nil {
+        t.Fatalf("getCertificate: %v", err)
+    }
+    if got := leafCommonName(t, cert); got != "original" {
+        t.Fatalf("got CN %q, want %q", got, "original")
+    }
+
+    rotatedCertPath, rotatedKeyPath := writeSelfSignedCert(t, dir, "rotated")
+    if err := os.Rename(rotatedCertPath, certPath); err != nil {
+        t.Fatalf("rename cert: %v", err)
+    }
+    if err := os.Rename(rotatedKeyPath, keyPath); err != nil {
+        t.Fatalf("rename key: %v", err)
+    }
+
+    if err := rc.reload(); err != nil {
+        t.Fatalf("reload: %v", err)
+    }
+    cert, err = rc.getCertificate(nil)
+    if err != nil {
+        t.Fatalf("getCertificate after reload: %v", err)
+    }
+    if got := leafCommonName(t, cert); got != "rotated" {
+        t.Fatalf("got CN %q after reload, want %q", got, "rotated")
+    }
+}
+
+func TestRotatingCertificateWatchPicksUpRotation(t *testing.T) {
+    dir := t.TempDir()
+    certPath, keyPath := writeSelfSignedCert(t, dir, "original")
+
+    rc, err := newRotatingCertificate(certPath, keyPath)
+    if err != nil {
+        t.Fatalf("newRotatingCertificate: %v", err)
+    }
+
+    rotatedCertPath, rotatedKeyPath := writeSelfSignedCert(t, dir, "rotated")
+    if err := os.Rename(rotatedCertPath, certPath); err != nil {
+        t.Fatalf("rename cert: %v", err)
+    }
+    if err := os.Rename(rotatedKeyPath, keyPath); err != nil {
+        t.Fatalf("rename key: %v", err)
+    }
+
+    stop := make(chan struct{})
+    done := make(chan struct{})
+    go func() {
+        rc.watch(5*time.Millisecond, stop)
+        close(done)
+    }()
+
+    deadline := time.Now().Add(time.Second)
+    for time.Now().Before(deadline) {
+        cert, err := rc.getCertificate(nil)
+        if err == nil && leafCommonName(t, cert) == "rotated" {
+            close(stop)
+            <-done
+            return
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+    close(stop)
+    <-done
+    t.Fatalf("watch did not pick up the rotated certificate in time")
+}
+
+func TestContextWithPeerSubjectCommonName(t *testing.T) {
+    req := &http.Request{TLS: &tls.ConnectionState{
+        PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "alice"}}},
+    }}
+
+    ctx := contextWithPeerSubject(context.Background(), req)
+    subject, ok := peerSubjectFromContext(ctx)
+    if !ok || subject != "alice" {
+        t.Fatalf("got (%q, %v), want (%q, true)", subject, ok, "alice")
+    }
+}
+
+func TestContextWithPeerSubjectFallsBackToSAN(t *testing.T) {
+    req := &http.Request{TLS: &tls.ConnectionState{
+        PeerCertificates: []*x509.Certificate{{DNSNames: []string{"device.example"}}},
+    }}
+
+    ctx := contextWithPeerSubject(context.Background(), req)
+    subject, ok := peerSubjectFromContext(ctx)
+    if !ok || subject != "device.example" {
+        t.Fatalf("got (%q, %v), want (%q, true)", subject, ok, "device.example")
+    }
+}
+
+func TestContextWithPeerSubjectNoTLS(t *testing.T) {
+    req := &http.Request{}
+
+    ctx := contextWithPeerSubject(context.Background(), req)
+    if _, ok := peerSubjectFromContext(ctx); ok {
+        t.Fatalf("expected no peer subject for a non-TLS request")
+    }
+}
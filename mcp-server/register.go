@@ -0,0 +1,187 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "reflect"
+    "strings"
+
+    "github.com/mark3labs/mcp-go/mcp"
+    "github.com/mark3labs/mcp-go/server"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// RegisterFunc registers fn as an MCP tool named name on s, deriving both the
+// tool's argument schema and its decoding/encoding from fn's signature
+// instead of requiring a hand-written mcp.NewTool/handler pair.
+//
+// fn must have the shape func(context.Context, Args) (T, error), where Args
+// is a struct whose fields are tagged `mcp:"argName,required,desc=..."`.
+// Supported field kinds are string, bool, signed integers and floats;
+// unsigned integer fields are not supported. T may be a
+// string (rendered as tool text) or any JSON-marshalable value (rendered as
+// a JSON tool text result). RegisterFunc panics if fn does not match this
+// shape, since that is a programmer error caught at startup, not runtime
+// input.
+func RegisterFunc(s *server.MCPServer, name, desc string, fn any) {
+    fnType := reflect.TypeOf(fn)
+    fnValue := reflect.ValueOf(fn)
+
+    if fnType.Kind() != reflect.Func || fnType.NumIn() != 2 || fnType.NumOut() != 2 || fnType.In(0) != contextType {
+        panic(fmt.Sprintf("RegisterFunc(%s): fn must be func(context.Context, Args) (T, error)", name))
+    }
+    if !fnType.Out(1).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+        panic(fmt.Sprintf("RegisterFunc(%s): fn's second return value must be error", name))
+    }
+
+    argsType := fnType.In(1)
+    if argsType.Kind() != reflect.Struct {
+        panic(fmt.Sprintf("RegisterFunc(%s): fn's argument must be a struct", name))
+    }
+
+    fields, err := parseArgFields(argsType)
+    if err != nil {
+        panic(fmt.Sprintf("RegisterFunc(%s): %v", name, err))
+    }
+
+    opts := []mcp.ToolOption{mcp.WithDescription(desc)}
+    for _, f := range fields {
+        opts = append(opts, f.toolOption())
+    }
+    tool := mcp.NewTool(name, opts...)
+
+    s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+        args := reflect.New(argsType).Elem()
+        for _, f := range fields {
+            if err := f.decode(args, request.Params.Arguments); err != nil {
+                return mcp.NewToolResultError(err.Error()), nil
+            }
+        }
+
+        out := fnValue.Call([]reflect.Value{reflect.ValueOf(ctx), args})
+        if errVal := out[1].Interface(); errVal != nil {
+            return mcp.NewToolResultError(errVal.(error).Error()), nil
+        }
+        return toolResultFor(out[0].Interface())
+    })
+}
+
+// argField is the parsed form of one `mcp:"..."` struct tag.
+type argField struct {
+    index    int
+    argName  string
+    required bool
+    desc     string
+    kind     reflect.Kind
+}
+
+func parseArgFields(argsType reflect.Type) ([]argField, error) {
+    var fields []argField
+    for i := 0; i < argsType.NumField(); i++ {
+        sf := argsType.Field(i)
+        tag, ok := sf.Tag.Lookup("mcp")
+        if !ok {
+            continue
+        }
+        parts := strings.Split(tag, ",")
+        if len(parts) == 0 || parts[0] == "" {
+            return nil, fmt.Errorf("field %s: mcp tag must start with an argument name", sf.Name)
+        }
+
+        f := argField{index: i, argName: parts[0], kind: sf.Type.Kind()}
+        for _, opt := range parts[1:] {
+            switch {
+            case opt == "required":
+                f.required = true
+            case strings.HasPrefix(opt, "desc="):
+                f.desc = strings.TrimPrefix(opt, "desc=")
+            }
+        }
+        fields = append(fields, f)
+    }
+    return fields, nil
+}
+
+func (f argField) toolOption() mcp.ToolOption {
+    var propOpts []mcp.PropertyOption
+    if f.desc != "" {
+        propOpts = append(propOpts, mcp.Description(f.desc))
+    }
+    if f.required {
+        propOpts = append(propOpts, mcp.Required())
+    }
+
+    switch {
+    case f.kind == reflect.Bool:
+        return mcp.WithBoolean(f.argName, propOpts...)
+    case f.kind == reflect.String:
+        return mcp.WithString(f.argName, propOpts...)
+    case isNumericKind(f.kind):
+        return mcp.WithNumber(f.argName, propOpts...)
+    default:
+        panic(fmt.Sprintf("mcp tag on argument %q: unsupported field kind %s", f.argName, f.kind))
+    }
+}
+
+func (f argField) decode(args reflect.Value, arguments map[string]any) error {
+    raw, ok := arguments[f.argName]
+    if !ok {
+        if f.required {
+            return fmt.Errorf("%s is required", f.argName)
+        }
+        return nil
+    }
+
+    field := args.Field(f.index)
+    switch {
+    case f.kind == reflect.Bool:
+        v, ok := raw.(bool)
+        if !ok {
+            return fmt.Errorf("%s must be a boolean", f.argName)
+        }
+        field.SetBool(v)
+    case f.kind == reflect.String:
+        v, ok := raw.(string)
+        if !ok {
+            return fmt.Errorf("%s must be a string", f.argName)
+        }
+        field.SetString(v)
+    case isNumericKind(f.kind):
+        v, ok := raw.(float64)
+        if !ok {
+            return fmt.Errorf("%s must be a number", f.argName)
+        }
+        if field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64 {
+            field.SetFloat(v)
+        } else {
+            field.SetInt(int64(v))
+        }
+    }
+    return nil
+}
+
+func isNumericKind(k reflect.Kind) bool {
+    switch k {
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Float32, reflect.Float64:
+        return true
+    default:
+        return false
+    }
+}
+
+// toolResultFor renders a tool's typed return value into a *mcp.CallToolResult:
+// strings become text as-is, everything else is JSON-encoded.
+func toolResultFor(v any) (*mcp.CallToolResult, error) {
+    if s, ok := v.(string); ok {
+        return mcp.NewToolResultText(s), nil
+    }
+
+    data, err := json.Marshal(v)
+    if err != nil {
+        return nil, fmt.Errorf("marshal tool result: %w", err)
+    }
+    return mcp.NewToolResultText(string(data)), nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/mark3labs/mcp-go/server"
+)
+
+type greetArgs struct {
+    Name string `mcp:"name,required,desc=Name of the person to greet"`
+    Loud bool   `mcp:"loud,desc=Whether to shout the greeting"`
+}
+
+func TestParseArgFields(t *testing.T) {
+    fields, err := parseArgFields(reflect.TypeOf(greetArgs{}))
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if len(fields) != 2 {
+        t.Fatalf("got %d fields, want 2", len(fields))
+    }
+    if fields[0].argName != "name" || !fields[0].required {
+        t.Fatalf("got %+v, want required name field", fields[0])
+    }
+    if fields[1].argName != "loud" || fields[1].required {
+        t.Fatalf("got %+v, want optional loud field", fields[1])
+    }
+}
+
+func TestArgFieldDecode(t *testing.T) {
+    fields, err := parseArgFields(reflect.TypeOf(greetArgs{}))
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    args := reflect.New(reflect.TypeOf(greetArgs{})).Elem()
+    arguments := map[string]any{"name": "Ada", "loud": true}
+    for _, f := range fields {
+        if err := f.decode(args, arguments); err != nil {
+            t.Fatalf("decode %s: %v", f.argName, err)
+        }
+    }
+
+    got := args.Interface().(greetArgs)
+    if got.Name != "Ada" || !got.Loud {
+        t.Fatalf("got %+v", got)
+    }
+}
+
+func TestArgFieldDecodeMissingRequired(t *testing.T) {
+    fields, err := parseArgFields(reflect.TypeOf(greetArgs{}))
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    args := reflect.New(reflect.TypeOf(greetArgs{})).Elem()
+    if err := fields[0].decode(args, map[string]any{}); err == nil {
+        t.Fatalf("expected an error for a missing required field")
+    }
+}
+
+func TestRegisterFuncPanicsOnWrongFirstParam(t *testing.T) {
+    defer func() {
+        r := recover()
+        if r == nil {
+            t.Fatalf("expected a panic when fn's first parameter isn't context.Context")
+        }
+    }()
+
+    s := server.NewMCPServer("test", "0.0.0")
+    RegisterFunc(s, "greet", "Greet someone", func(notCtx string, args greetArgs) (string, error) {
+        return "", nil
+    })
+}
+
+func TestToolResultForString(t *testing.T) {
+    result, err := toolResultFor("hello")
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if result.IsError {
+        t.Fatalf("unexpected error result: %+v", result)
+    }
+}